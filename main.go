@@ -1,9 +1,18 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -43,6 +52,8 @@ func main() {
 	batchSize := getInt(config, "batch_size", 10)
 	operation := getString(config, "operation", "analyze")
 	debug := getBool(config, "debug", false)
+	percentiles := getFloatSlice(config, "percentiles", []float64{0.25, 0.5, 0.75})
+	outputFormat := getString(config, "output_format", "json")
 
 	fmt.Printf("📊 Processing for user %d with batch size %d\n", userID, batchSize)
 
@@ -50,21 +61,30 @@ func main() {
 		fmt.Printf("Debug mode enabled\n") // DEBUG - this line gets removed
 	}
 
-	// Generate sample data
-	rawData := generateSampleData()
-	fmt.Printf("📥 Generated %d data points\n", len(rawData))
+	// Load the data from whichever source the config selects
+	dataSource := buildDataSource(config)
+	rawData, err := dataSource.Load()
+	if err != nil {
+		panic("Failed to load data: " + err.Error())
+	}
+	fmt.Printf("📥 Loaded %d data points\n", len(rawData))
 
 	// Process the data based on operation type
 	var result map[string]interface{}
 	switch operation {
 	case "analyze":
-		result = analyzeData(rawData, batchSize)
+		result = analyzeData(rawData, batchSize, percentiles)
 	case "transform":
 		result = transformData(rawData, batchSize)
 	case "aggregate":
-		result = aggregateData(rawData, batchSize)
+		bucketEdges := resolveBucketEdges(rawData, getFloatSlice(config, "buckets", nil), getBool(config, "log_scale", false), getInt(config, "bucket_count", 5))
+		result = aggregateData(rawData, batchSize, bucketEdges)
+	case "compare":
+		sampleA := getFloatSlice(config, "a", nil)
+		sampleB := getFloatSlice(config, "b", nil)
+		result = compareData(sampleA, sampleB)
 	default:
-		result = analyzeData(rawData, batchSize)
+		result = analyzeData(rawData, batchSize, percentiles)
 	}
 
 	// Add metadata to result
@@ -75,8 +95,12 @@ func main() {
 	result["data_points"] = len(rawData)
 	result["debug_mode"] = debug
 
-	// Set the result for the executor to retrieve
-	ScriptResult = result
+	// Encode into the requested wire format and set the result for the executor to retrieve
+	encoded, err := encodeResult(outputFormat, result)
+	if err != nil {
+		panic("Failed to encode result: " + err.Error())
+	}
+	ScriptResult = encoded
 
 	fmt.Printf("✅ Processing complete! Operation: %s, Results: %d items\n",
 		operation, len(result))
@@ -84,8 +108,9 @@ func main() {
 	fmt.Printf("Debug: Final result keys = %d\n", len(result)) // DEBUG
 }
 
-// analyzeData performs statistical analysis on the data
-func analyzeData(data []float64, batchSize int) map[string]interface{} {
+// analyzeData performs statistical analysis on the data, including the
+// variance/stdev/mode/percentile subsystem implemented by the stats* helpers.
+func analyzeData(data []float64, batchSize int, percentiles []float64) map[string]interface{} {
 	if len(data) == 0 {
 		return map[string]interface{}{
 			"error": "no data to analyze",
@@ -108,18 +133,23 @@ func analyzeData(data []float64, batchSize int) map[string]interface{} {
 
 	mean := sum / float64(len(data))
 	median := calculateMedian(sortedData)
+	popVariance, sampleVariance := statsVariance(data, mean)
 
 	return map[string]interface{}{
-		"type":          "analysis",
-		"count":         len(data),
-		"sum":           roundToTwo(sum),
-		"mean":          roundToTwo(mean),
-		"median":        roundToTwo(median),
-		"min":           min,
-		"max":           max,
-		"range":         max - min,
-		"percentile_25": sortedData[len(sortedData)/4],
-		"percentile_75": sortedData[3*len(sortedData)/4],
+		"type":            "analysis",
+		"count":           len(data),
+		"sum":             roundToTwo(sum),
+		"mean":            roundToTwo(mean),
+		"median":          roundToTwo(median),
+		"min":             min,
+		"max":             max,
+		"range":           max - min,
+		"variance":        roundToTwo(popVariance),
+		"sample_variance": roundToTwo(sampleVariance),
+		"stdev":           roundToTwo(math.Sqrt(popVariance)),
+		"sample_stdev":    roundToTwo(math.Sqrt(sampleVariance)),
+		"mode":            statsMode(data),
+		"percentiles":     statsPercentiles(sortedData, percentiles),
 	}
 }
 
@@ -159,43 +189,498 @@ func transformData(data []float64, batchSize int) map[string]interface{} {
 }
 
 // aggregateData groups and summarizes the data
-func aggregateData(data []float64, batchSize int) map[string]interface{} {
-	ranges := map[string]int{
-		"0-25":   0,
-		"26-50":  0,
-		"51-75":  0,
-		"76-100": 0,
-		"100+":   0,
+func aggregateData(data []float64, batchSize int, bucketEdges []float64) map[string]interface{} {
+	if len(data) == 0 {
+		return map[string]interface{}{
+			"error": "no data to aggregate",
+		}
 	}
 
-	for _, value := range data {
-		switch {
-		case value <= 25:
-			ranges["0-25"]++
-		case value <= 50:
-			ranges["26-50"]++
-		case value <= 75:
-			ranges["51-75"]++
-		case value <= 100:
-			ranges["76-100"]++
-		default:
-			ranges["100+"]++
+	edges := make([]float64, len(bucketEdges))
+	copy(edges, bucketEdges)
+	sort.Float64s(edges)
+
+	total := len(data)
+	buckets := make([]histBucket, 0, len(edges)+1)
+
+	lower := math.Inf(-1)
+	cumulative := 0
+	for i := 0; i <= len(edges); i++ {
+		upper := math.Inf(1)
+		if i < len(edges) {
+			upper = edges[i]
 		}
+
+		count := 0
+		for _, value := range data {
+			if value > lower && value <= upper {
+				count++
+			}
+		}
+		cumulative += count
+
+		buckets = append(buckets, histBucket{
+			label:      bucketLabel(lower, upper),
+			lower:      lower,
+			upper:      upper,
+			count:      count,
+			cumulative: cumulative,
+		})
+
+		lower = upper
 	}
 
-	total := len(data)
-	percentages := make(map[string]float64)
-	for key, count := range ranges {
-		percentages[key] = roundToTwo((float64(count) / float64(total)) * 100.0)
+	bucketResults := make([]map[string]interface{}, len(buckets))
+	for i, bucket := range buckets {
+		bucketResults[i] = map[string]interface{}{
+			"label":            bucket.label,
+			"count":            bucket.count,
+			"cumulative_count": bucket.cumulative,
+			"percentage":       roundToTwo(float64(bucket.count) / float64(total) * 100.0),
+			"cdf":              roundToTwo(float64(bucket.cumulative) / float64(total) * 100.0),
+		}
+	}
+
+	quantiles := map[string]float64{
+		"p50": interpolateQuantile(buckets, total, 0.50),
+		"p90": interpolateQuantile(buckets, total, 0.90),
+		"p99": interpolateQuantile(buckets, total, 0.99),
 	}
 
 	return map[string]interface{}{
 		"type":          "aggregation",
 		"total_count":   total,
-		"ranges":        ranges,
-		"percentages":   percentages,
-		"largest_group": findLargestGroup(ranges),
+		"buckets":       bucketResults,
+		"quantiles":     quantiles,
+		"largest_group": findLargestBucket(buckets),
+	}
+}
+
+// histBucket is one histogram bucket covering the half-open interval
+// (lower, upper].
+type histBucket struct {
+	label      string
+	lower      float64
+	upper      float64
+	count      int
+	cumulative int
+}
+
+// bucketLabel renders a bucket's bounds, using open-ended notation for the
+// first/last buckets when their bound is unbounded.
+func bucketLabel(lower, upper float64) string {
+	switch {
+	case math.IsInf(lower, -1):
+		return fmt.Sprintf("<=%s", formatBound(upper))
+	case math.IsInf(upper, 1):
+		return fmt.Sprintf(">%s", formatBound(lower))
+	default:
+		return fmt.Sprintf("%s-%s", formatBound(lower), formatBound(upper))
+	}
+}
+
+func formatBound(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// interpolateQuantile estimates the p-th quantile from the histogram via
+// linear interpolation within the bucket that contains its target rank:
+// q ≈ lower + (target_rank - cum_below) / bucket_count * width.
+func interpolateQuantile(buckets []histBucket, total int, p float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	targetRank := p * float64(total)
+	cumBelow := 0.0
+
+	for _, bucket := range buckets {
+		if float64(bucket.cumulative) >= targetRank {
+			return interpolateWithinBucket(bucket, targetRank, cumBelow)
+		}
+		cumBelow = float64(bucket.cumulative)
+	}
+
+	return interpolateWithinBucket(buckets[len(buckets)-1], targetRank, cumBelow)
+}
+
+// interpolateWithinBucket linearly interpolates the quantile inside bucket,
+// falling back to whichever bound is finite when a bound is open-ended (an
+// open-ended bucket can't be interpolated, and a ±Inf value would break
+// downstream JSON encoding).
+func interpolateWithinBucket(bucket histBucket, targetRank, cumBelow float64) float64 {
+	switch {
+	case math.IsInf(bucket.lower, -1) && math.IsInf(bucket.upper, 1):
+		return 0
+	case math.IsInf(bucket.lower, -1):
+		return bucket.upper
+	case bucket.count == 0 || math.IsInf(bucket.upper, 1):
+		return bucket.lower
+	default:
+		width := bucket.upper - bucket.lower
+		return bucket.lower + (targetRank-cumBelow)/float64(bucket.count)*width
+	}
+}
+
+// resolveBucketEdges picks the histogram edges to aggregate with: explicit
+// config wins, otherwise a log-scale range derived from the data, otherwise
+// the default linear edges.
+func resolveBucketEdges(data []float64, configured []float64, logScale bool, bucketCount int) []float64 {
+	if len(configured) > 0 {
+		return configured
+	}
+	if logScale {
+		return logScaleBuckets(data, bucketCount)
+	}
+	return []float64{25, 50, 75, 100}
+}
+
+// logScaleBuckets builds bucketCount geometrically spaced upper bounds
+// spanning the positive range of data.
+func logScaleBuckets(data []float64, bucketCount int) []float64 {
+	if bucketCount < 1 {
+		bucketCount = 5
+	}
+
+	minVal := math.Inf(1)
+	maxVal := 0.0
+	for _, value := range data {
+		if value > 0 && value < minVal {
+			minVal = value
+		}
+		if value > maxVal {
+			maxVal = value
+		}
+	}
+	if math.IsInf(minVal, 1) || maxVal <= 0 || minVal >= maxVal {
+		return []float64{25, 50, 75, 100}
+	}
+
+	logMin := math.Log(minVal)
+	logMax := math.Log(maxVal)
+	step := (logMax - logMin) / float64(bucketCount)
+
+	edges := make([]float64, bucketCount)
+	for i := 1; i <= bucketCount; i++ {
+		edges[i-1] = math.Exp(logMin + step*float64(i))
+	}
+	return edges
+}
+
+// compareData runs a benchstat-style A/B comparison of two sample sets,
+// reporting the mean delta alongside a Welch's t-test for the difference
+// of means.
+func compareData(a, b []float64) map[string]interface{} {
+	if len(a) == 0 || len(b) == 0 {
+		return map[string]interface{}{
+			"error": "compare requires non-empty \"a\" and \"b\" sample sets",
+		}
+	}
+
+	meanA := statsMean(a)
+	meanB := statsMean(b)
+	_, sampleVarA := statsVariance(a, meanA)
+	_, sampleVarB := statsVariance(b, meanB)
+
+	na := float64(len(a))
+	nb := float64(len(b))
+
+	meanDelta := meanB - meanA
+	pctChange := 0.0
+	if meanA != 0 {
+		pctChange = (meanDelta / meanA) * 100.0
+	}
+
+	pooledStdev := 0.0
+	if na+nb > 2 {
+		pooledStdev = math.Sqrt(((na-1)*sampleVarA + (nb-1)*sampleVarB) / (na + nb - 2))
+	}
+
+	result := map[string]interface{}{
+		"type":         "comparison",
+		"count_a":      len(a),
+		"count_b":      len(b),
+		"mean_a":       roundToTwo(meanA),
+		"mean_b":       roundToTwo(meanB),
+		"mean_delta":   roundToTwo(meanDelta),
+		"pct_change":   roundToTwo(pctChange),
+		"pooled_stdev": roundToTwo(pooledStdev),
+	}
+
+	se := math.Sqrt(sampleVarA/na + sampleVarB/nb)
+	if se == 0 || len(a) < 2 || len(b) < 2 {
+		result["significant"] = false
+		return result
+	}
+
+	t := meanDelta / se
+	df := welchSatterthwaiteDF(sampleVarA, na, sampleVarB, nb)
+	pValue := studentTTwoTailedPValue(t, df)
+	tCrit := studentTCriticalValue(df, 0.05)
+
+	result["t"] = roundToTwo(t)
+	result["df"] = roundToTwo(df)
+	result["p_value"] = roundToFour(pValue)
+	result["ci_low"] = roundToTwo(meanDelta - tCrit*se)
+	result["ci_high"] = roundToTwo(meanDelta + tCrit*se)
+	result["significant"] = pValue < 0.05
+
+	return result
+}
+
+// welchSatterthwaiteDF estimates the effective degrees of freedom for
+// Welch's t-test from the two samples' sizes and variances.
+func welchSatterthwaiteDF(varA, na, varB, nb float64) float64 {
+	termA := varA / na
+	termB := varB / nb
+
+	denominator := (termA*termA)/(na-1) + (termB*termB)/(nb-1)
+	if denominator == 0 {
+		return na + nb - 2
+	}
+	return (termA + termB) * (termA + termB) / denominator
+}
+
+// studentTTwoTailedPValue computes the two-tailed p-value for statistic t
+// with df degrees of freedom via the regularized incomplete beta function:
+// p = I_x(df/2, 1/2) where x = df/(df+t^2).
+func studentTTwoTailedPValue(t, df float64) float64 {
+	x := df / (df + t*t)
+	return regularizedIncompleteBeta(df/2, 0.5, x)
+}
+
+// studentTCriticalValue finds the t value whose two-tailed p-value equals
+// alpha, by bisecting the (monotonically decreasing in |t|) p-value curve.
+func studentTCriticalValue(df, alpha float64) float64 {
+	lo, hi := 0.0, 1000.0
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if studentTTwoTailedPValue(mid, df) > alpha {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// regularizedIncompleteBeta computes I_x(a, b), the regularized incomplete
+// beta function, via the continued-fraction expansion from Numerical Recipes.
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lgammaSum, _ := math.Lgamma(a + b)
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	logBeta := lgammaSum - lgammaA - lgammaB + a*math.Log(x) + b*math.Log(1-x)
+	front := math.Exp(logBeta)
+
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(a, b, x) / a
+	}
+	return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction used by
+// regularizedIncompleteBeta (Lentz's algorithm).
+func betaContinuedFraction(a, b, x float64) float64 {
+	const maxIterations = 200
+	const epsilon = 3e-14
+	const minValue = 1e-300
+
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < minValue {
+		d = minValue
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		m2 := float64(2 * m)
+
+		aa := float64(m) * (b - float64(m)) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < minValue {
+			d = minValue
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < minValue {
+			c = minValue
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + float64(m)) * (qab + float64(m)) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < minValue {
+			d = minValue
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < minValue {
+			c = minValue
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// Encoder renders a result map into a specific wire format.
+type Encoder interface {
+	Encode(result map[string]interface{}) (interface{}, error)
+}
+
+// encodeResult picks an Encoder by name and applies it to result.
+func encodeResult(format string, result map[string]interface{}) (interface{}, error) {
+	var encoder Encoder
+	switch format {
+	case "prometheus":
+		encoder = prometheusEncoder{}
+	case "csv":
+		encoder = csvEncoder{}
+	default:
+		encoder = jsonEncoder{}
+	}
+	return encoder.Encode(result)
+}
+
+// jsonEncoder passes the result through unchanged, matching the executor's
+// default nested-JSON handling.
+type jsonEncoder struct{}
+
+func (e jsonEncoder) Encode(result map[string]interface{}) (interface{}, error) {
+	return result, nil
+}
+
+// prometheusEncoder renders scalar and nested-numeric fields as a flat
+// Prometheus text exposition, labeled with user_id and operation.
+type prometheusEncoder struct{}
+
+func (e prometheusEncoder) Encode(result map[string]interface{}) (interface{}, error) {
+	labels := fmt.Sprintf(`user_id="%v",operation="%v"`, result["user_id"], result["operation"])
+
+	var lines []string
+	for _, key := range sortedKeys(result) {
+		switch value := result[key].(type) {
+		case float64:
+			lines = append(lines, prometheusLine(key, labels, strconv.FormatFloat(value, 'f', -1, 64)))
+		case int:
+			lines = append(lines, prometheusLine(key, labels, strconv.Itoa(value)))
+		case map[string]float64:
+			for _, subKey := range sortedFloatKeys(value) {
+				name := sanitizeMetricName(key) + "_" + sanitizeMetricName(subKey)
+				lines = append(lines, prometheusLine(name, labels, strconv.FormatFloat(value[subKey], 'f', -1, 64)))
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func prometheusLine(name, labels, value string) string {
+	return fmt.Sprintf("%s{%s} %s", sanitizeMetricName(name), labels, value)
+}
+
+func sanitizeMetricName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFloatKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
+}
+
+// csvEncoder renders the transform/aggregate row sets (batch_results,
+// buckets) as CSV; any other result is rendered as a single scalar-field row.
+type csvEncoder struct{}
+
+func (e csvEncoder) Encode(result map[string]interface{}) (interface{}, error) {
+	if rows, ok := result["batch_results"].([]map[string]interface{}); ok {
+		return rowsToCSV(rows)
+	}
+	if rows, ok := result["buckets"].([]map[string]interface{}); ok {
+		return rowsToCSV(rows)
+	}
+	return scalarFieldsToCSV(result)
+}
+
+func rowsToCSV(rows []map[string]interface{}) (string, error) {
+	if len(rows) == 0 {
+		return "", nil
+	}
+	header := sortedKeys(rows[0])
+	return writeCSV(header, rows)
+}
+
+func scalarFieldsToCSV(result map[string]interface{}) (string, error) {
+	var header []string
+	for _, key := range sortedKeys(result) {
+		switch result[key].(type) {
+		case string, float64, int, bool:
+			header = append(header, key)
+		}
+	}
+	return writeCSV(header, []map[string]interface{}{result})
+}
+
+func writeCSV(header []string, rows []map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(header); err != nil {
+		return "", err
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for i, key := range header {
+			record[i] = fmt.Sprintf("%v", row[key])
+		}
+		if err := writer.Write(record); err != nil {
+			return "", err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
 
 // Helper functions
@@ -221,22 +706,290 @@ func calculateMedian(sortedData []float64) float64 {
 	return sortedData[n/2]
 }
 
-func findLargestGroup(ranges map[string]int) string {
+// statsMean returns the arithmetic mean of data.
+func statsMean(data []float64) float64 {
+	sum := 0.0
+	for _, value := range data {
+		sum += value
+	}
+	return sum / float64(len(data))
+}
+
+// statsVariance returns the population and sample variance of data around mean.
+func statsVariance(data []float64, mean float64) (population float64, sample float64) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+
+	sumSquares := 0.0
+	for _, value := range data {
+		diff := value - mean
+		sumSquares += diff * diff
+	}
+
+	population = sumSquares / float64(len(data))
+	if len(data) > 1 {
+		sample = sumSquares / float64(len(data)-1)
+	}
+	return population, sample
+}
+
+// statsMode returns the most frequent value in data, breaking ties by
+// whichever value was seen first.
+func statsMode(data []float64) float64 {
+	counts := make(map[float64]int)
+	order := make([]float64, 0, len(data))
+
+	for _, value := range data {
+		if counts[value] == 0 {
+			order = append(order, value)
+		}
+		counts[value]++
+	}
+
+	mode := order[0]
+	maxCount := counts[mode]
+	for _, value := range order {
+		if counts[value] > maxCount {
+			mode = value
+			maxCount = counts[value]
+		}
+	}
+	return mode
+}
+
+// statsPercentiles computes each requested percentile from sortedData using
+// the nearest-rank method: index = ceil(p*n) - 1, clamped to [0, n-1].
+func statsPercentiles(sortedData []float64, percentiles []float64) map[string]float64 {
+	result := make(map[string]float64, len(percentiles))
+	n := len(sortedData)
+
+	for _, p := range percentiles {
+		idx := int(math.Ceil(p*float64(n))) - 1
+		if idx < 0 {
+			idx = 0
+		}
+		if idx > n-1 {
+			idx = n - 1
+		}
+		result[percentileKey(p)] = sortedData[idx]
+	}
+	return result
+}
+
+// percentileKey formats a percentile fraction (e.g. 0.95) as "p95".
+func percentileKey(p float64) string {
+	return fmt.Sprintf("p%s", strconv.FormatFloat(p*100, 'f', -1, 64))
+}
+
+func findLargestBucket(buckets []histBucket) string {
 	maxCount := 0
-	maxKey := ""
+	maxLabel := ""
 
-	for key, count := range ranges {
-		if count > maxCount {
-			maxCount = count
-			maxKey = key
+	for _, bucket := range buckets {
+		if bucket.count > maxCount {
+			maxCount = bucket.count
+			maxLabel = bucket.label
 		}
 	}
 
-	return maxKey
+	return maxLabel
 }
 
 func roundToTwo(value float64) float64 {
-	return float64(int(value*100+0.5)) / 100
+	return math.Round(value*100) / 100
+}
+
+func roundToFour(value float64) float64 {
+	return math.Round(value*10000) / 10000
+}
+
+// DataSource loads the []float64 a script operates on.
+type DataSource interface {
+	Load() ([]float64, error)
+}
+
+// buildDataSource selects a DataSource from config["data_source"]. With no
+// "data_source" configured it falls back to the inline sample data.
+func buildDataSource(config map[string]interface{}) DataSource {
+	raw, exists := config["data_source"]
+	if !exists {
+		return inlineDataSource{values: generateSampleData()}
+	}
+
+	sourceConfig, ok := raw.(map[string]interface{})
+	if !ok {
+		return inlineDataSource{values: generateSampleData()}
+	}
+
+	switch getString(sourceConfig, "type", "inline") {
+	case "csv_url":
+		return csvURLDataSource{url: getString(sourceConfig, "url", "")}
+	case "jsonl_url":
+		return jsonlURLDataSource{
+			url:   getString(sourceConfig, "url", ""),
+			field: getString(sourceConfig, "field", "value"),
+		}
+	case "synthetic":
+		return syntheticDataSource{
+			distribution: getString(sourceConfig, "distribution", "uniform"),
+			n:            getInt(sourceConfig, "n", 50),
+			seed:         int64(getInt(sourceConfig, "seed", 1)),
+			mean:         getFloat(sourceConfig, "mean", 50),
+			stdev:        getFloat(sourceConfig, "stdev", 15),
+			min:          getFloat(sourceConfig, "min", 0),
+			max:          getFloat(sourceConfig, "max", 100),
+			lambda:       getFloat(sourceConfig, "lambda", 1.0),
+		}
+	default:
+		return inlineDataSource{values: getFloatSlice(sourceConfig, "values", generateSampleData())}
+	}
+}
+
+// inlineDataSource returns a fixed slice handed in directly from config.
+type inlineDataSource struct {
+	values []float64
+}
+
+func (s inlineDataSource) Load() ([]float64, error) {
+	return s.values, nil
+}
+
+// httpDataSourceClient bounds how long a csv_url/jsonl_url fetch may hang.
+var httpDataSourceClient = &http.Client{Timeout: 30 * time.Second}
+
+// fetchURL GETs url and returns its body, failing on transport errors and
+// non-2xx statuses.
+func fetchURL(url string) (io.ReadCloser, error) {
+	resp, err := httpDataSourceClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// csvURLDataSource fetches a CSV document over HTTP and parses the first
+// numeric field of each row.
+type csvURLDataSource struct {
+	url string
+}
+
+func (s csvURLDataSource) Load() ([]float64, error) {
+	if s.url == "" {
+		return nil, fmt.Errorf("csv_url data source requires a \"url\"")
+	}
+
+	body, err := fetchURL(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching csv: %w", err)
+	}
+	defer body.Close()
+
+	records, err := csv.NewReader(body).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing csv: %w", err)
+	}
+
+	var values []float64
+	for _, record := range records {
+		for _, field := range record {
+			value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				continue
+			}
+			values = append(values, value)
+			break
+		}
+	}
+	return values, nil
+}
+
+// jsonlURLDataSource fetches a newline-delimited document over HTTP, reading
+// either a bare number or a configurable field from a JSON object per line.
+type jsonlURLDataSource struct {
+	url   string
+	field string
+}
+
+func (s jsonlURLDataSource) Load() ([]float64, error) {
+	if s.url == "" {
+		return nil, fmt.Errorf("jsonl_url data source requires a \"url\"")
+	}
+
+	body, err := fetchURL(s.url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching jsonl: %w", err)
+	}
+	defer body.Close()
+
+	var values []float64
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if value, err := strconv.ParseFloat(line, 64); err == nil {
+			values = append(values, value)
+			continue
+		}
+
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			continue
+		}
+		if raw, ok := record[s.field]; ok {
+			if value, ok := raw.(float64); ok {
+				values = append(values, value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading jsonl: %w", err)
+	}
+	return values, nil
+}
+
+// syntheticDataSource generates n seeded pseudo-random values from a uniform,
+// normal, or exponential distribution, for reproducible synthetic inputs.
+type syntheticDataSource struct {
+	distribution string
+	n            int
+	seed         int64
+	mean, stdev  float64
+	min, max     float64
+	lambda       float64
+}
+
+func (s syntheticDataSource) Load() ([]float64, error) {
+	rng := rand.New(rand.NewSource(s.seed))
+	values := make([]float64, s.n)
+
+	switch s.distribution {
+	case "normal":
+		for i := range values {
+			values[i] = rng.NormFloat64()*s.stdev + s.mean
+		}
+	case "exponential":
+		lambda := s.lambda
+		if lambda <= 0 {
+			lambda = 1.0
+		}
+		for i := range values {
+			values[i] = rng.ExpFloat64() / lambda
+		}
+	default: // "uniform"
+		span := s.max - s.min
+		for i := range values {
+			values[i] = s.min + rng.Float64()*span
+		}
+	}
+	return values, nil
 }
 
 func generateSampleData() []float64 {
@@ -259,6 +1012,15 @@ func getInt(config map[string]interface{}, key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getFloat(config map[string]interface{}, key string, defaultValue float64) float64 {
+	if value, exists := config[key]; exists {
+		if floatVal, ok := value.(float64); ok {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getString(config map[string]interface{}, key string, defaultValue string) string {
 	if value, exists := config[key]; exists {
 		if strVal, ok := value.(string); ok {
@@ -276,3 +1038,26 @@ func getBool(config map[string]interface{}, key string, defaultValue bool) bool
 	}
 	return defaultValue
 }
+
+func getFloatSlice(config map[string]interface{}, key string, defaultValue []float64) []float64 {
+	value, exists := config[key]
+	if !exists {
+		return defaultValue
+	}
+
+	rawSlice, ok := value.([]interface{})
+	if !ok {
+		return defaultValue
+	}
+
+	result := make([]float64, 0, len(rawSlice))
+	for _, item := range rawSlice {
+		if floatVal, ok := item.(float64); ok {
+			result = append(result, floatVal)
+		}
+	}
+	if len(result) == 0 {
+		return defaultValue
+	}
+	return result
+}